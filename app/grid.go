@@ -0,0 +1,58 @@
+package main
+
+import "sort"
+
+// groupPanesByRow returns the indices of panes grouped into rows by their
+// Row field, each row's indices sorted left-to-right by Col, and rows
+// sorted top-to-bottom by Row.
+func groupPanesByRow(panes []pane) [][]int {
+	byRow := map[int][]int{}
+	var rowKeys []int
+	for i, p := range panes {
+		if _, ok := byRow[p.row]; !ok {
+			rowKeys = append(rowKeys, p.row)
+		}
+		byRow[p.row] = append(byRow[p.row], i)
+	}
+	sort.Ints(rowKeys)
+
+	rows := make([][]int, len(rowKeys))
+	for i, key := range rowKeys {
+		indices := byRow[key]
+		sort.Slice(indices, func(a, b int) bool {
+			return panes[indices[a]].col < panes[indices[b]].col
+		})
+		rows[i] = indices
+	}
+	return rows
+}
+
+// columnWidths splits totalWidth across weighted columns. Each column
+// gets a weight-proportional share; the last column absorbs whatever
+// remainder integer division leaves so the columns always sum exactly to
+// totalWidth.
+func columnWidths(totalWidth int, weights []int) []int {
+	if len(weights) == 0 {
+		return nil
+	}
+
+	sum := 0
+	for _, w := range weights {
+		sum += w
+	}
+	if sum <= 0 {
+		sum = len(weights)
+	}
+
+	widths := make([]int, len(weights))
+	used := 0
+	for i, w := range weights {
+		if i == len(weights)-1 {
+			widths[i] = totalWidth - used
+			continue
+		}
+		widths[i] = totalWidth * w / sum
+		used += widths[i]
+	}
+	return widths
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// helpText lists the key bindings available from the base layout.
+const helpText = `Key bindings
+
+  tab          cycle focus between panes and the output pane
+  space        toggle the focused menu item
+  up/down      move the focused pane's selection
+  enter        run the focused Commands pane's selection
+  ctrl+p       open the command palette
+  ctrl+j       open the scrollback journal
+  ctrl+h       open this help
+  esc / q      close the focused overlay
+  q            quit (base layout only)
+`
+
+// helpView is a full-screen pushable view listing the app's key bindings.
+type helpView struct {
+	viewport viewport.Model
+}
+
+// newHelpView builds a help overlay sized to the terminal.
+func newHelpView(width, height int) helpView {
+	vp := viewport.New(width, height)
+	vp.SetContent(helpText)
+	return helpView{viewport: vp}
+}
+
+func (h helpView) Init() tea.Cmd { return nil }
+
+func (h helpView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h.viewport.Width = msg.Width - 2
+		h.viewport.Height = msg.Height - 2
+		return h, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			return h, closeWindowCmd
+		}
+	}
+
+	var cmd tea.Cmd
+	h.viewport, cmd = h.viewport.Update(msg)
+	return h, cmd
+}
+
+func (h helpView) View() string {
+	return focusedBorderStyle.Width(h.viewport.Width).Height(h.viewport.Height).Render(h.viewport.View())
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// journalView is a full-screen pushable view for reviewing scrollback
+// captured from the server's event stream (port 9002) without losing the
+// base layout's pane state underneath it.
+type journalView struct {
+	viewport viewport.Model
+}
+
+// newJournalView snapshots content (typically the output pane's current
+// text) into a dedicated full-screen viewport.
+func newJournalView(content string, width, height int) journalView {
+	vp := viewport.New(width, height)
+	vp.SetContent(content)
+	return journalView{viewport: vp}
+}
+
+func (j journalView) Init() tea.Cmd { return nil }
+
+func (j journalView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		j.viewport.Width = msg.Width - 2
+		j.viewport.Height = msg.Height - 2
+		return j, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			return j, closeWindowCmd
+		}
+	}
+
+	var cmd tea.Cmd
+	j.viewport, cmd = j.viewport.Update(msg)
+	return j, cmd
+}
+
+func (j journalView) View() string {
+	return focusedBorderStyle.Width(j.viewport.Width).Height(j.viewport.Height).Render(j.viewport.View())
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// netClient owns the two TCP connections to the backing server: 9001 for
+// outbound commands, 9002 for the inbound event stream. It is held on the
+// model behind a pointer so that Bubble Tea's by-value model copies all
+// share the same underlying connections instead of racing on them.
+//
+// epoch identifies which "generation" of connection a netClient represents.
+// Reconnecting closes the old client and hands the model a new one with a
+// bumped epoch; the old client's in-flight connectCmd/readLineCmd may still
+// be blocked in a syscall and will report a message after Close() unblocks
+// them. Update compares a message's epoch against the model's current one
+// and drops anything stale instead of letting it corrupt connection state.
+type netClient struct {
+	conn9001 net.Conn
+	conn9002 net.Conn
+	reader   *bufio.Reader
+	epoch    int
+}
+
+// connectedMsg reports that both ports dialed successfully.
+type connectedMsg struct{ epoch int }
+
+// connectErrMsg reports a dial or read failure on either port.
+type connectErrMsg struct {
+	epoch int
+	err   error
+}
+
+// serverLineMsg carries one newline-terminated line read from port 9002.
+type serverLineMsg struct {
+	epoch int
+	line  string
+}
+
+// sendResultMsg reports the outcome of writing a command to port 9001.
+type sendResultMsg struct{ err error }
+
+// connectCmd dials both ports and reports the outcome as a tea.Msg.
+func connectCmd(nc *netClient) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		nc.conn9001, err = net.Dial("tcp", port9001)
+		if err != nil {
+			return connectErrMsg{epoch: nc.epoch, err: err}
+		}
+		nc.conn9002, err = net.Dial("tcp", port9002)
+		if err != nil {
+			return connectErrMsg{epoch: nc.epoch, err: err}
+		}
+		nc.reader = bufio.NewReader(nc.conn9002)
+		return connectedMsg{epoch: nc.epoch}
+	}
+}
+
+// readLineCmd blocks for the next line from port 9002. Update re-issues
+// this command after each line so the read loop keeps going.
+func readLineCmd(nc *netClient) tea.Cmd {
+	return func() tea.Msg {
+		line, err := nc.reader.ReadString('\n')
+		if err != nil {
+			return connectErrMsg{epoch: nc.epoch, err: err}
+		}
+		return serverLineMsg{epoch: nc.epoch, line: line}
+	}
+}
+
+// Close closes both connections, ignoring errors, so a reconnect can swap
+// in a fresh netClient without leaking the old sockets.
+func (nc *netClient) Close() {
+	if nc.conn9001 != nil {
+		nc.conn9001.Close()
+	}
+	if nc.conn9002 != nil {
+		nc.conn9002.Close()
+	}
+}
+
+// sendCmd writes command to port 9001 and reports any write error.
+func sendCmd(nc *netClient, command string) tea.Cmd {
+	return func() tea.Msg {
+		if nc.conn9001 == nil {
+			return sendResultMsg{err: fmt.Errorf("not connected to %s", port9001)}
+		}
+		_, err := nc.conn9001.Write([]byte(command + "\n"))
+		return sendResultMsg{err: err}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// builtinCommands are always available in the command palette, in
+// addition to whatever the active layout's command panes contribute.
+var builtinCommands = []string{"quit", "clear output", "reconnect", "toggle timestamps"}
+
+// paletteItem is a single executable command surfaced in the palette,
+// regardless of whether it came from a configured pane or a built-in.
+type paletteItem struct {
+	name string
+}
+
+func (p paletteItem) Title() string       { return p.name }
+func (p paletteItem) Description() string { return "" }
+func (p paletteItem) FilterValue() string { return p.name }
+
+// paletteSelectMsg is emitted when the user picks a command from the
+// palette; Update executes it on the same path as Enter on a Commands
+// pane.
+type paletteSelectMsg struct{ command string }
+
+func paletteSelectCmd(command string) tea.Cmd {
+	return func() tea.Msg { return paletteSelectMsg{command: command} }
+}
+
+// paletteView is the ctrl+p command palette: a fuzzy-filterable list of
+// every known command, deduplicated across configured panes and the
+// built-ins above.
+type paletteView struct {
+	list list.Model
+}
+
+func newPaletteView(commands []string, width, height int) paletteView {
+	seen := NewSet[string]()
+	items := make([]list.Item, 0, len(commands))
+	for _, c := range commands {
+		if seen.Contains(c) {
+			continue
+		}
+		seen.Add(c)
+		items = append(items, paletteItem{name: c})
+	}
+
+	l := list.New(items, list.NewDefaultDelegate(), width, height)
+	l.Title = "Command Palette"
+	l.SetFilteringEnabled(true)
+	l.SetShowHelp(false)
+
+	return paletteView{list: l}
+}
+
+func (p paletteView) Init() tea.Cmd { return nil }
+
+func (p paletteView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.list.SetSize(msg.Width-2, msg.Height-2)
+		return p, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			if p.list.FilterState() == list.Filtering {
+				break
+			}
+			return p, closeWindowCmd
+		case "enter":
+			if p.list.FilterState() == list.Filtering {
+				break
+			}
+			if item, ok := p.list.SelectedItem().(paletteItem); ok {
+				return p, tea.Batch(closeWindowCmd, paletteSelectCmd(item.name))
+			}
+			return p, closeWindowCmd
+		}
+	}
+
+	var cmd tea.Cmd
+	p.list, cmd = p.list.Update(msg)
+	return p, cmd
+}
+
+func (p paletteView) View() string {
+	return focusedBorderStyle.Render(p.list.View())
+}
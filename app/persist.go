@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// scrollbackLines caps how many trailing lines of output are persisted.
+const scrollbackLines = 200
+
+// persistedState is the on-disk shape written on quit and restored by
+// main at the start of the next run, before the program starts.
+type persistedState struct {
+	Selected   []string `json:"selected"`
+	Scrollback []string `json:"scrollback"`
+}
+
+// defaultStateFile returns the state file path under the user's config
+// directory, used when -state-file isn't given.
+func defaultStateFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "go_bubbletea_TUI_sample-state.json"
+	}
+	return filepath.Join(dir, "go_bubbletea_TUI_sample", "state.json")
+}
+
+// selectionKey identifies a menu item's persisted selection state.
+func selectionKey(paneTitle, itemTitle string) string {
+	return paneTitle + "|" + itemTitle
+}
+
+// loadState reads a previously persisted state file. A missing file
+// isn't an error - it just means there's nothing to restore yet.
+func loadState(path string) (persistedState, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return persistedState{}, nil
+	}
+	if err != nil {
+		return persistedState{}, err
+	}
+	var s persistedState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return persistedState{}, err
+	}
+	return s, nil
+}
+
+// saveState writes state to path, creating parent directories as needed.
+func saveState(path string, state persistedState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// exportState snapshots the current selection set and output scrollback
+// for persistence across runs.
+func (m model) exportState() persistedState {
+	var selected []string
+	for _, p := range m.topPanes {
+		for _, it := range p.items.Items() {
+			if mi, ok := it.(menuItem); ok && mi.selected {
+				selected = append(selected, selectionKey(p.title, mi.title))
+			}
+		}
+	}
+
+	lines := strings.Split(strings.Join(m.outputHistory, ""), "\n")
+	if len(lines) > scrollbackLines {
+		lines = lines[len(lines)-scrollbackLines:]
+	}
+
+	return persistedState{Selected: selected, Scrollback: lines}
+}
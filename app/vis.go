@@ -1,29 +1,33 @@
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
 	"io"
-	"net"
 	"os"
-	"sync"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/JKI757/go_bubbletea_TUI_sample/config"
 )
 
 type model struct {
 	focusIndex        int
 	topPanes          []pane
 	outputPane        viewport.Model
+	outputHistory     []string // output scrollback, capped at the last scrollbackLines entries; outputPane only ever shows a window onto this
 	outputPaneFocused bool
-	mu                sync.Mutex
-	conn9001          net.Conn
-	conn9002          net.Conn
+	net               *netClient
+	connected         bool
+	showTimestamps    bool
 	width             int
 	height            int
+	windows           *WindowManager
 }
 
 type pane struct {
@@ -31,6 +35,9 @@ type pane struct {
 	items   list.Model
 	isMenu  bool
 	focused bool
+	row     int
+	col     int
+	weight  int
 }
 
 const (
@@ -93,30 +100,44 @@ func (d customDelegate) Render(w io.Writer, m list.Model, index int, item list.I
 	}
 }
 
-// Initialize the panes
-func initialModel() model {
-	pane1ItemList := []string{"Option A", "Option B", "Option C"}
-	pane2ItemList := []string{"Option X", "Option Y", "Option Z"}
-	commands := []string{"Cmd 1", "Cmd 2", "Cmd 3", "Exit"}
-	pane4Itemlist := []string{"Opt 1", "Opt 2", "Opt 3"}
-
-	panes := []pane{
-		{"Pane 1", createList("Pane 1", pane1ItemList, 10, true), true, true},
-		{"Pane 2", createList("Pane 2", pane2ItemList, 10, false), true, false},
-		{"Commands", createList("Commands", commands, 10, false), false, false},
-		{"Pane 4", createList("Pane 4", pane4Itemlist, 10, false), true, false},
+// Initialize the panes from a layout config, restoring any previously
+// selected items from a persisted run.
+func initialModel(layout *config.Layout, selected *Set[string]) model {
+	panes := make([]pane, len(layout.Panes))
+	for i, pc := range layout.Panes {
+		panes[i] = pane{
+			title:   pc.Title,
+			items:   createList(pc.Title, pc.Items, 10, i == 0, selected),
+			isMenu:  pc.Kind != config.KindCommands,
+			focused: i == 0,
+			row:     pc.Row,
+			col:     pc.Col,
+			weight:  pc.Weight,
+		}
 	}
 
+	welcome := "Welcome to the TUI!"
 	output := viewport.New(100, 20)
-	output.SetContent("Welcome to the TUI!")
-
-	return model{topPanes: panes, outputPane: output, focusIndex: 0}
+	output.SetContent(welcome)
+
+	return model{
+		topPanes:      panes,
+		outputPane:    output,
+		outputHistory: []string{welcome},
+		focusIndex:    0,
+		windows:       NewWindowManager(),
+		net:           &netClient{},
+	}
 }
 
-func createList(title string, items []string, height int, focused bool) list.Model {
+func createList(title string, items []config.Item, height int, focused bool, selected *Set[string]) list.Model {
 	itemList := make([]list.Item, len(items))
 	for i, item := range items {
-		itemList[i] = menuItem{title: item}
+		mi := menuItem{title: item.Title, desc: item.Desc}
+		if selected != nil && selected.Contains(selectionKey(title, item.Title)) {
+			mi.selected = true
+		}
+		itemList[i] = mi
 	}
 
 	// Ensure the list height is sufficient to display all items
@@ -136,6 +157,19 @@ func createList(title string, items []string, height int, focused bool) list.Mod
 	return l
 }
 
+// appendOutput records text in the output history, dropping the oldest
+// entries beyond scrollbackLines, and refreshes the output pane's content
+// from it. viewport.Model.View() only ever returns
+// the currently visible slice, so the history must be tracked separately
+// rather than rebuilt from the viewport on every append.
+func (m *model) appendOutput(text string) {
+	m.outputHistory = append(m.outputHistory, text)
+	if len(m.outputHistory) > scrollbackLines {
+		m.outputHistory = m.outputHistory[len(m.outputHistory)-scrollbackLines:]
+	}
+	m.outputPane.SetContent(strings.Join(m.outputHistory, ""))
+}
+
 // Update the delegates when focus changes
 func (m *model) updatePaneDelegates() {
 	for i := range m.topPanes {
@@ -143,77 +177,154 @@ func (m *model) updatePaneDelegates() {
 	}
 }
 
+// collectCommands aggregates every item from the layout's command panes
+// with the built-in commands, for the ctrl+p command palette.
+func (m model) collectCommands() []string {
+	var commands []string
+	for _, p := range m.topPanes {
+		if p.isMenu {
+			continue
+		}
+		for _, item := range p.items.Items() {
+			commands = append(commands, item.FilterValue())
+		}
+	}
+	return append(commands, builtinCommands...)
+}
+
+// executeCommand runs a named command, whether it was selected from a
+// Commands pane or the ctrl+p command palette. Built-ins are handled
+// locally; anything else is sent to the server on port 9001.
+func (m model) executeCommand(command string) (tea.Model, tea.Cmd) {
+	switch command {
+	case "Exit", "quit":
+		return m, tea.Quit
+	case "clear output":
+		m.outputHistory = nil
+		m.outputPane.SetContent("")
+		return m, nil
+	case "reconnect":
+		oldEpoch := m.net.epoch
+		m.net.Close()
+		m.net = &netClient{epoch: oldEpoch + 1}
+		m.connected = false
+		return m, connectCmd(m.net)
+	case "toggle timestamps":
+		m.showTimestamps = !m.showTimestamps
+		return m, nil
+	default:
+		return m, sendCmd(m.net, command)
+	}
+}
+
 // BubbleTea's Init function
 func (m model) Init() tea.Cmd {
 	// Initialize default dimensions
 	m.width = 80  // Default terminal width
 	m.height = 24 // Default terminal height
-	for i := range m.topPanes {
-		m.topPanes[i].items.SetWidth(m.width/4 - 2)
-	}
-	m.outputPane.Width = m.width - 2
-	m.outputPane.Height = m.height / 2
+	m.layoutPanes()
 
-	go m.connectToServer()
-	return nil
+	return connectCmd(m.net)
 }
 
-func (m *model) connectToServer() {
-	var err error
-	m.conn9001, err = net.Dial("tcp", port9001)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error connecting to port 9001: %v\n", err)
-	}
-	m.conn9002, err = net.Dial("tcp", port9002)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error connecting to port 9002: %v\n", err)
-		return
+// layoutPanes resizes each top pane's list to match the current terminal
+// dimensions, honoring each pane's row/col position and weight.
+func (m *model) layoutPanes() {
+	rows := groupPanesByRow(m.topPanes)
+	topHeight := (m.height / 2) - 2
+	rowHeight := topHeight
+	if len(rows) > 0 {
+		rowHeight = topHeight / len(rows)
 	}
-	go m.listenToServer()
-}
-func (m *model) listenToServer() {
-	reader := bufio.NewReader(m.conn9002)
-	for {
-		message, err := reader.ReadString('\n')
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading from port 9002: %v\n", err)
-			return
+
+	for _, row := range rows {
+		weights := make([]int, len(row))
+		for i, idx := range row {
+			weights[i] = m.topPanes[idx].weight
+		}
+		widths := columnWidths(m.width, weights)
+
+		for i, idx := range row {
+			numItems := len(m.topPanes[idx].items.Items())
+			displayHeight := rowHeight
+			if numItems < rowHeight {
+				displayHeight = numItems
+			}
+			m.topPanes[idx].items.SetHeight(displayHeight)
+			m.topPanes[idx].items.SetWidth(widths[i] - 2)
 		}
-		m.mu.Lock()
-		m.outputPane.SetContent(m.outputPane.View() + message)
-		m.mu.Unlock()
 	}
-}
 
-func (m *model) addToOutputPane(txt string) {
-	m.mu.Lock()
-	m.outputPane.SetContent(m.outputPane.View() + txt)
-	m.mu.Unlock()
+	m.outputPane.Width = m.width - 2
+	m.outputPane.Height = m.height / 2
 }
 
 // Update the model based on messages
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(closeWindowMsg); ok {
+		m.windows.CloseFocused()
+		return m, nil
+	}
+
 	switch msg := msg.(type) {
+	case connectedMsg:
+		if msg.epoch != m.net.epoch {
+			return m, nil // stale message from a superseded connection
+		}
+		m.connected = true
+		return m, readLineCmd(m.net)
+	case connectErrMsg:
+		if msg.epoch != m.net.epoch {
+			return m, nil // stale message from a superseded connection
+		}
+		m.connected = false
+		m.appendOutput("\nConnection error: " + msg.err.Error())
+		return m, nil
+	case serverLineMsg:
+		if msg.epoch != m.net.epoch {
+			return m, nil // stale message from a superseded connection
+		}
+		line := msg.line
+		if m.showTimestamps {
+			line = time.Now().Format("15:04:05 ") + line
+		}
+		m.appendOutput(line)
+		return m, readLineCmd(m.net)
+	case sendResultMsg:
+		if msg.err != nil {
+			m.appendOutput("\nError sending command: " + msg.err.Error())
+		}
+		return m, nil
+	case paletteSelectMsg:
+		return m.executeCommand(msg.command)
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.layoutPanes()
 
-		// Divide the top row into four equal-width panes
-		// paneWidth := m.width / 4
-		paneHeight := (m.height / 2) - 2 // Consistent height for all panes
-		for i := range m.topPanes {
-			// Adjust height dynamically based on content
-			numItems := len(m.topPanes[i].items.Items())
-			displayHeight := paneHeight
-			if numItems < paneHeight {
-				displayHeight = numItems
-			}
-			m.topPanes[i].items.SetHeight(displayHeight)
-		} // Output pane spans the full width and the remaining height
-		m.outputPane.Width = m.width - 2
-		m.outputPane.Height = m.height / 2
+		if m.windows.GetNumberOpen() > 0 {
+			cmd := m.windows.Update(msg)
+			return m, cmd
+		}
+		return m, nil
 	case tea.KeyMsg:
+		// A stacked view (journal, help, command palette, ...) owns all
+		// key input until it closes itself via closeWindowMsg.
+		if m.windows.GetNumberOpen() > 0 {
+			cmd := m.windows.Update(msg)
+			return m, cmd
+		}
+
 		switch msg.String() {
+		case "ctrl+j":
+			cmd := m.windows.Open(newJournalView(strings.Join(m.outputHistory, ""), m.width-2, m.height-2))
+			return m, cmd
+		case "ctrl+p":
+			cmd := m.windows.Open(newPaletteView(m.collectCommands(), m.width-2, m.height-2))
+			return m, cmd
+		case "ctrl+h":
+			cmd := m.windows.Open(newHelpView(m.width-2, m.height-2))
+			return m, cmd
 		case "tab":
 			previousFocusIndex := m.focusIndex
 			m.focusIndex = (m.focusIndex + 1) % (len(m.topPanes) + 1) // Include output pane
@@ -233,17 +344,25 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Update the delegates
 			m.updatePaneDelegates()
 		case " ":
-			if m.focusIndex < len(m.topPanes) && m.topPanes[m.focusIndex].isMenu {
-				index := m.topPanes[m.focusIndex].items.Index()
-				items := m.topPanes[m.focusIndex].items.Items()
-				item := items[index].(menuItem)                // Get the menuItem
-				item.selected = !item.selected                 // Toggle selection
-				items[index] = item                            // Update the item in the slice
-				m.topPanes[m.focusIndex].items.SetItems(items) // Apply the changes
+			if m.focusIndex < len(m.topPanes) {
+				if m.topPanes[m.focusIndex].isMenu {
+					items := m.topPanes[m.focusIndex].items.Items()
+					index := m.topPanes[m.focusIndex].items.Index()
+					if index < len(items) {
+						item := items[index].(menuItem)                // Get the menuItem
+						item.selected = !item.selected                 // Toggle selection
+						items[index] = item                            // Update the item in the slice
+						m.topPanes[m.focusIndex].items.SetItems(items) // Apply the changes
+					}
+				}
+				var cmd tea.Cmd
+				m.topPanes[m.focusIndex].items, cmd = m.topPanes[m.focusIndex].items.Update(msg)
+				return m, cmd
+			} else if m.outputPaneFocused {
+				var cmd tea.Cmd
+				m.outputPane, cmd = m.outputPane.Update(msg)
+				return m, cmd
 			}
-			var cmd tea.Cmd
-			m.topPanes[m.focusIndex].items, cmd = m.topPanes[m.focusIndex].items.Update(msg)
-			return m, cmd
 		case "up", "down":
 			// Arrow keys are automatically handled by list.Model when focused
 			if m.focusIndex < len(m.topPanes) {
@@ -257,23 +376,24 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case "enter":
 			if m.focusIndex < len(m.topPanes) && !m.topPanes[m.focusIndex].isMenu {
+				items := m.topPanes[m.focusIndex].items.Items()
 				index := m.topPanes[m.focusIndex].items.Index()
-				command := m.topPanes[m.focusIndex].items.Items()[index].FilterValue()
-
-				if command == "Exit" {
-					return m, tea.Quit
-				}
-
-				if m.conn9001 != nil {
-					_, err := m.conn9001.Write([]byte(command + "\n"))
-					if err != nil {
-						m.outputPane.SetContent(m.outputPane.View() + "\nError sending command: " + err.Error())
-					}
+				if index < len(items) {
+					return m.executeCommand(items[index].FilterValue())
 				}
 			}
 		case "q":
 			return m, tea.Quit
 		}
+	default:
+		// Any other message type (e.g. list.FilterMatchesMsg, spinner
+		// ticks, ...) belongs to whatever sub-component's command
+		// produced it. When a window is open, it owns those messages
+		// too, not just key/resize events.
+		if m.windows.GetNumberOpen() > 0 {
+			cmd := m.windows.Update(msg)
+			return m, cmd
+		}
 	}
 
 	// Update the focused component
@@ -290,22 +410,40 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the TUI
 func (m model) View() string {
-	var topPanes []string
-	paneHeight := (m.height / 2) - 2 // Consistent height for all panes
-
-	for _, pane := range m.topPanes {
-		// Adjust the styling based on focus
-		style := borderStyle
-		if pane.focused {
-			style = focusedBorderStyle
-		}
+	if m.windows.GetNumberOpen() > 0 {
+		return m.windows.View()
+	}
+
+	rows := groupPanesByRow(m.topPanes)
+	topHeight := (m.height / 2) - 2 // Consistent height for all panes
+	rowHeight := topHeight
+	if len(rows) > 0 {
+		rowHeight = topHeight / len(rows)
+	}
 
-		// Render each pane with the calculated height and width
-		topPanes = append(topPanes, style.Width(m.width/4-2).Height(paneHeight).Render(pane.items.View()))
+	var renderedRows []string
+	for _, row := range rows {
+		weights := make([]int, len(row))
+		for i, idx := range row {
+			weights[i] = m.topPanes[idx].weight
+		}
+		widths := columnWidths(m.width, weights)
+
+		var cells []string
+		for i, idx := range row {
+			p := m.topPanes[idx]
+			// Adjust the styling based on focus
+			style := borderStyle
+			if p.focused {
+				style = focusedBorderStyle
+			}
+			cells = append(cells, style.Width(widths[i]-2).Height(rowHeight).Render(p.items.View()))
+		}
+		renderedRows = append(renderedRows, lipgloss.JoinHorizontal(lipgloss.Top, cells...))
 	}
 
-	// Combine the top panes into a horizontal layout
-	topRow := lipgloss.JoinHorizontal(lipgloss.Top, topPanes...)
+	// Combine the pane rows into the grid
+	topRow := lipgloss.JoinVertical(lipgloss.Left, renderedRows...)
 
 	// Render the output pane with focus styling
 	var output string
@@ -315,14 +453,66 @@ func (m model) View() string {
 		output = borderStyle.Width(m.width - 2).Height(m.height / 2).Render(m.outputPane.View())
 	}
 
-	// Combine the top row and output pane
-	return lipgloss.JoinVertical(lipgloss.Left, topRow, output)
+	// Render a one-line connection status above the panes
+	status := "○ disconnected"
+	if m.connected {
+		status = "● connected"
+	}
+	status += "  (ctrl+h for help)"
+	statusBar := lipgloss.NewStyle().Padding(0, 1).Render(status)
+
+	// Combine the status bar, top row, and output pane
+	return lipgloss.JoinVertical(lipgloss.Left, statusBar, topRow, output)
 }
 
 func main() {
-	p := tea.NewProgram(initialModel())
-	if _, err := p.Run(); err != nil {
+	configPath := flag.String("config", "", "path to a TOML pane layout file (defaults to the built-in layout)")
+	stateFilePath := flag.String("state-file", defaultStateFile(), "path to the persisted selection/scrollback state file")
+	noPersist := flag.Bool("no-persist", false, "don't load or save persisted selection/scrollback state")
+	flag.Parse()
+
+	layout := config.Default()
+	if *configPath != "" {
+		loaded, err := config.Load(*configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		layout = loaded
+	}
+
+	var selected *Set[string]
+	var scrollback []string
+	if !*noPersist {
+		state, err := loadState(*stateFilePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading state: %v\n", err)
+		}
+		selected = NewSet[string]()
+		for _, key := range state.Selected {
+			selected.Add(key)
+		}
+		scrollback = state.Scrollback
+	}
+
+	m := initialModel(layout, selected)
+	if len(scrollback) > 0 {
+		m.outputHistory = []string{strings.Join(scrollback, "\n") + "\n"}
+		m.outputPane.SetContent(strings.Join(m.outputHistory, ""))
+	}
+
+	p := tea.NewProgram(m)
+	finalModel, err := p.Run()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error starting app: %v\n", err)
 		os.Exit(1)
 	}
+
+	if !*noPersist {
+		if fm, ok := finalModel.(model); ok {
+			if err := saveState(*stateFilePath, fm.exportState()); err != nil {
+				fmt.Fprintf(os.Stderr, "Error saving state: %v\n", err)
+			}
+		}
+	}
 }
@@ -0,0 +1,68 @@
+package main
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// closeWindowMsg is emitted by a pushed view when it wants to pop itself
+// off the WindowManager's stack (e.g. on "esc").
+type closeWindowMsg struct{}
+
+func closeWindowCmd() tea.Msg { return closeWindowMsg{} }
+
+// WindowManager maintains a stack of overlay tea.Model views on top of a
+// base layout. The top of the stack receives WindowSizeMsg/KeyMsg; when
+// the stack is empty, those messages fall through to the base layout.
+type WindowManager struct {
+	stack []tea.Model
+}
+
+// NewWindowManager creates an empty WindowManager.
+func NewWindowManager() *WindowManager {
+	return &WindowManager{}
+}
+
+// Open pushes view onto the stack and returns its Init command.
+func (w *WindowManager) Open(view tea.Model) tea.Cmd {
+	w.stack = append(w.stack, view)
+	return view.Init()
+}
+
+// CloseFocused pops the top-of-stack view, if any.
+func (w *WindowManager) CloseFocused() {
+	if len(w.stack) == 0 {
+		return
+	}
+	w.stack = w.stack[:len(w.stack)-1]
+}
+
+// Focused returns the top-of-stack view, or nil if the stack is empty.
+func (w *WindowManager) Focused() tea.Model {
+	if len(w.stack) == 0 {
+		return nil
+	}
+	return w.stack[len(w.stack)-1]
+}
+
+// GetNumberOpen reports how many views are currently stacked.
+func (w *WindowManager) GetNumberOpen() int {
+	return len(w.stack)
+}
+
+// Update routes msg to the top-of-stack view and stores its updated model.
+// It is a no-op when the stack is empty.
+func (w *WindowManager) Update(msg tea.Msg) tea.Cmd {
+	if len(w.stack) == 0 {
+		return nil
+	}
+	top := len(w.stack) - 1
+	updated, cmd := w.stack[top].Update(msg)
+	w.stack[top] = updated
+	return cmd
+}
+
+// View renders the top-of-stack view, or "" if the stack is empty.
+func (w *WindowManager) View() string {
+	if focused := w.Focused(); focused != nil {
+		return focused.View()
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+// Package config loads the declarative pane layout that drives the TUI,
+// so the app isn't limited to a single hardcoded set of panes.
+package config
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Kind distinguishes a checkbox menu pane from a pane that dispatches a
+// command on Enter.
+type Kind string
+
+const (
+	KindMenu     Kind = "menu"
+	KindCommands Kind = "commands"
+)
+
+// Item is a single selectable row within a pane.
+type Item struct {
+	Title string `toml:"title"`
+	Desc  string `toml:"desc"`
+}
+
+// Pane describes one pane's contents and its position in the grid. Row
+// and Col are zero-based; panes sharing a Row are laid out side by side,
+// sized proportionally to Weight.
+type Pane struct {
+	Title  string `toml:"title"`
+	Kind   Kind   `toml:"kind"`
+	Items  []Item `toml:"items"`
+	Row    int    `toml:"row"`
+	Col    int    `toml:"col"`
+	Weight int    `toml:"weight"`
+}
+
+// Layout is the top-level shape of a layout file.
+type Layout struct {
+	Panes []Pane `toml:"pane"`
+}
+
+// Load reads and parses a layout file in TOML format.
+func Load(path string) (*Layout, error) {
+	var l Layout
+	if _, err := toml.DecodeFile(path, &l); err != nil {
+		return nil, fmt.Errorf("loading layout %q: %w", path, err)
+	}
+	for i := range l.Panes {
+		if l.Panes[i].Weight <= 0 {
+			l.Panes[i].Weight = 1
+		}
+	}
+	return &l, nil
+}
+
+// Default returns the layout equivalent to the original hardcoded panes,
+// used when no -config flag is given.
+func Default() *Layout {
+	return &Layout{
+		Panes: []Pane{
+			{Title: "Pane 1", Kind: KindMenu, Row: 0, Col: 0, Weight: 1, Items: items("Option A", "Option B", "Option C")},
+			{Title: "Pane 2", Kind: KindMenu, Row: 0, Col: 1, Weight: 1, Items: items("Option X", "Option Y", "Option Z")},
+			{Title: "Commands", Kind: KindCommands, Row: 0, Col: 2, Weight: 1, Items: items("Cmd 1", "Cmd 2", "Cmd 3", "Exit")},
+			{Title: "Pane 4", Kind: KindMenu, Row: 0, Col: 3, Weight: 1, Items: items("Opt 1", "Opt 2", "Opt 3")},
+		},
+	}
+}
+
+func items(titles ...string) []Item {
+	out := make([]Item, len(titles))
+	for i, t := range titles {
+		out[i] = Item{Title: t}
+	}
+	return out
+}